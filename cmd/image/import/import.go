@@ -5,17 +5,45 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/IBM-Cloud/power-go-client/power/models"
 	"github.com/IBM/go-sdk-core/v4/core"
 	rcv2 "github.com/IBM/platform-services-go-sdk/resourcecontrollerv2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
 	"github.com/ppc64le-cloud/pvsadm/pkg"
 	"github.com/ppc64le-cloud/pvsadm/pkg/client"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client/image"
+	"github.com/ppc64le-cloud/pvsadm/pkg/image/validate"
+	"github.com/ppc64le-cloud/pvsadm/pkg/jobs"
 	"github.com/ppc64le-cloud/pvsadm/pkg/utils"
 )
 
+// sourceOpt holds the flags needed to stage a non-IBM source object into IBM
+// COS before handing it off to the PowerVS import API. It is kept separate
+// from pkg.ImageCMDOptions since it only applies to the staging pre-step.
+var sourceOpt image.StageOptions
+var keepStaging bool
+
+var (
+	wait         bool
+	waitTimeout  time.Duration
+	outputFormat string
+)
+
+var (
+	skipValidation bool
+	checksum       string
+	checksumFile   string
+)
+
+var validSourceProviders = []string{"ibmcos", "aws", "azure", "gcs"}
+
 var Cmd = &cobra.Command{
 	Use:   "import",
 	Short: "Import the image into PowerVS instances",
@@ -40,9 +68,18 @@ pvsadm image import -n upstream-core-lon04 -b <BUCKETNAME> -r <REGION> --storage
 pvsadm image import -n upstream-core-lon04 -b <BUCKETNAME> --object-name rhel-83-10032020.ova.gz --image-name test-image --ostype <OSTYPE> -r <REGION>
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var s3client *client.S3Client
+		if manifestPath != "" {
+			return runManifestImport(cmd, manifestPath)
+		}
+
+		for _, flag := range []string{"bucket", "image-name", "object-name", "region"} {
+			if cmd.Flags().Changed(flag) {
+				continue
+			}
+			return fmt.Errorf("required flag(s) %q not set", flag)
+		}
+
 		opt := pkg.ImageCMDOptions
-		apikey := pkg.Options.APIKey
 		//validate inputs
 		validOsType := []string{"aix", "ibmi", "redhat", "sles"}
 		validStorageType := []string{"tier3", "tier1"}
@@ -57,124 +94,236 @@ pvsadm image import -n upstream-core-lon04 -b <BUCKETNAME> --object-name rhel-83
 			os.Exit(1)
 		}
 
-		bxCli, err := client.NewClient(apikey)
-		if err != nil {
-			return err
+		if sourceOpt.Provider == "" {
+			sourceOpt.Provider = image.SourceIBMCOS
 		}
-
-		auth, err := core.NewIamAuthenticator(apikey, "", "", "", false, nil)
-		if err != nil {
-			return err
+		sourceOpt.Provider = image.SourceProvider(strings.ToLower(string(sourceOpt.Provider)))
+		if !utils.Contains(validSourceProviders, string(sourceOpt.Provider)) {
+			klog.Errorf("Provide valid source-provider.. allowable values are [ibmcos, aws, azure, gcs]")
+			os.Exit(1)
 		}
 
-		resourceController, err := client.NewResourceControllerV2(&rcv2.ResourceControllerV2Options{
-			Authenticator: auth,
-		})
-		if err != nil {
-			return err
-		}
+		_, err := doImport(cmd, opt)
+		return err
+	},
+}
 
-		instances, _, err := resourceController.ResourceControllerV2.ListResourceInstances(resourceController.ResourceControllerV2.NewListResourceInstancesOptions().SetType("service_instance"))
-		if err != nil {
-			return err
-		}
+// doImport drives a single import end to end: locating the COS bucket,
+// staging a non-IBM source object if configured, pre-flight validation,
+// resolving credentials, submitting the job, and optionally waiting for it
+// to complete. It is shared by the single-image flow above and the manifest
+// batch flow in manifest.go.
+func doImport(cmd *cobra.Command, opt pkg.ImageOptions) (string, error) {
+	apikey := pkg.Options.APIKey
 
-		// Step 1: Find where COS for the bucket
-		cosOfBucket := func(resources []rcv2.ResourceInstance) *rcv2.ResourceInstance {
-			for _, resource := range resources {
-				if strings.Contains(*resource.Crn, "cloud-object-storage") {
-					s3client, err = client.NewS3Client(bxCli, *resource.Name, opt.Region)
-					if err != nil {
-						continue
-					}
-					buckets, err := s3client.S3Session.ListBuckets(nil)
-					if err != nil {
-						continue
-					}
-					for _, bucket := range buckets.Buckets {
-						if *bucket.Name == opt.BucketName {
-							return &resource
-						}
+	bxCli, err := client.NewClient(apikey)
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := core.NewIamAuthenticator(apikey, "", "", "", false, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resourceController, err := client.NewResourceControllerV2(&rcv2.ResourceControllerV2Options{
+		Authenticator: auth,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	instances, _, err := resourceController.ResourceControllerV2.ListResourceInstances(resourceController.ResourceControllerV2.NewListResourceInstancesOptions().SetType("service_instance"))
+	if err != nil {
+		return "", err
+	}
+
+	var s3client *client.S3Client
+
+	// Step 1: Find where COS for the bucket
+	cosOfBucket := func(resources []rcv2.ResourceInstance) *rcv2.ResourceInstance {
+		for _, resource := range resources {
+			if strings.Contains(*resource.Crn, "cloud-object-storage") {
+				s3client, err = client.NewS3Client(bxCli, *resource.Name, opt.Region)
+				if err != nil {
+					continue
+				}
+				buckets, err := s3client.S3Session.ListBuckets(nil)
+				if err != nil {
+					continue
+				}
+				for _, bucket := range buckets.Buckets {
+					if *bucket.Name == opt.BucketName {
+						return &resource
 					}
 				}
 			}
-			return nil
-		}(instances.Resources)
-
-		if cosOfBucket == nil {
-			return fmt.Errorf("failed to find the COS instance for the bucket mentioned: %s", opt.BucketName)
 		}
-		klog.Infof("%s bucket found in the %s[ID:%s] COS instance", opt.BucketName, *cosOfBucket.Name, *cosOfBucket.ID)
+		return nil
+	}(instances.Resources)
 
-		//Step 2: Check if s3 object exists
-		objectExists := s3client.CheckIfObjectExists(opt.BucketName, opt.ImageFilename)
-		if !objectExists {
-			return fmt.Errorf("failed to found the object %s in %s bucket", opt.ImageFilename, opt.BucketName)
-		}
-		klog.Infof("%s object found in the %s bucket\n", opt.ImageFilename, opt.BucketName)
+	if cosOfBucket == nil {
+		return "", fmt.Errorf("failed to find the COS instance for the bucket mentioned: %s", opt.BucketName)
+	}
+	klog.Infof("%s bucket found in the %s[ID:%s] COS instance", opt.BucketName, *cosOfBucket.Name, *cosOfBucket.ID)
 
-		if opt.AccessKey == "" || opt.SecretKey == "" {
-			// Step 3: Check if Service Credential exists for the found COS instance
-			keys, _, err := resourceController.ResourceControllerV2.ListResourceKeys(resourceController.ResourceControllerV2.NewListResourceKeysOptions().SetName(opt.ServiceCredName))
-			if err != nil {
-				return fmt.Errorf("failed to list the service credentials: %v", err)
-			}
+	// Step 1.5: If the source object lives outside IBM COS, stage it into
+	// the target COS bucket first, since PowerVS can only import from COS.
+	// doImport may run concurrently (once per manifest entry), so take a
+	// per-call copy of the shared sourceOpt flags instead of mutating them.
+	if sourceOpt.Provider != image.SourceIBMCOS && sourceOpt.Provider != "" {
+		stageOpt := sourceOpt
+		stageOpt.DestBucketName = opt.BucketName
+		stageOpt.DestObjectName = opt.ImageFilename
+		stageOpt.KeepStaging = keepStaging
 
-			cred := new(rcv2.Credentials)
-			if len(keys.Resources) == 0 {
-				// Create the service credential if does not exist
-				klog.Infof("Auto Generating the COS Service credential for importing the image with name: %s", opt.ServiceCredName)
-				createResourceKeyOptions := &client.CreateResourceKeyOptions{
-					CreateResourceKeyOptions: resourceController.ResourceControllerV2.NewCreateResourceKeyOptions(opt.ServiceCredName, *cosOfBucket.ID),
-					Parameters:               map[string]interface{}{"HMAC": true},
-				}
+		uploader := s3manager.NewUploaderWithClient(s3client.S3Session)
+		if _, err := image.Stage(cmd.Context(), uploader, stageOpt); err != nil {
+			return "", err
+		}
+		klog.Infof("staged %s object from %s into %s/%s", opt.ImageFilename, stageOpt.Provider, opt.BucketName, opt.ImageFilename)
 
-				key, _, err := resourceController.CreateResourceKey(createResourceKeyOptions)
+		if !keepStaging {
+			defer func() {
+				_, err := s3client.S3Session.DeleteObject(&s3.DeleteObjectInput{
+					Bucket: aws.String(opt.BucketName),
+					Key:    aws.String(opt.ImageFilename),
+				})
 				if err != nil {
-					return err
+					klog.Warningf("failed to clean up staged object %s/%s: %v", opt.BucketName, opt.ImageFilename, err)
 				}
-				cred = key.Credentials
+			}()
+		}
+	}
 
-			} else {
-				// Use the service credential already created
-				klog.Infof("Reading the existing service credential: %s", opt.ServiceCredName)
-				cred = keys.Resources[0].Credentials
-			}
+	//Step 2: Check if s3 object exists
+	objectExists := s3client.CheckIfObjectExists(opt.BucketName, opt.ImageFilename)
+	if !objectExists {
+		return "", fmt.Errorf("failed to found the object %s in %s bucket", opt.ImageFilename, opt.BucketName)
+	}
+	klog.Infof("%s object found in the %s bucket\n", opt.ImageFilename, opt.BucketName)
 
-			jsonString, err := json.Marshal(cred.GetProperty("cos_hmac_keys"))
-			if err != nil {
-				return err
+	if !skipValidation {
+		klog.Infof("validating %s/%s before submitting the import job", opt.BucketName, opt.ImageFilename)
+		if err := validate.Validate(s3client.S3Session, opt.BucketName, opt.ImageFilename, validate.Options{
+			SHA256:       checksum,
+			ChecksumFile: checksumFile,
+		}); err != nil {
+			return "", fmt.Errorf("pre-flight validation failed: %v", err)
+		}
+	}
+
+	if opt.AccessKey == "" || opt.SecretKey == "" {
+		// Step 3: Check if Service Credential exists for the found COS instance
+		keys, _, err := resourceController.ResourceControllerV2.ListResourceKeys(resourceController.ResourceControllerV2.NewListResourceKeysOptions().SetName(opt.ServiceCredName))
+		if err != nil {
+			return "", fmt.Errorf("failed to list the service credentials: %v", err)
+		}
+
+		cred := new(rcv2.Credentials)
+		if len(keys.Resources) == 0 {
+			// Create the service credential if does not exist
+			klog.Infof("Auto Generating the COS Service credential for importing the image with name: %s", opt.ServiceCredName)
+			createResourceKeyOptions := &client.CreateResourceKeyOptions{
+				CreateResourceKeyOptions: resourceController.ResourceControllerV2.NewCreateResourceKeyOptions(opt.ServiceCredName, *cosOfBucket.ID),
+				Parameters:               map[string]interface{}{"HMAC": true},
 			}
-			h := struct {
-				AccessKeyID string `json:"access_key_id"`
-				SecretKeyID string `json:"secret_access_key"`
-			}{}
-			err = json.Unmarshal(jsonString, &h)
+
+			key, _, err := resourceController.CreateResourceKey(createResourceKeyOptions)
 			if err != nil {
-				klog.Errorf("failed to unmarshal the access credentials from the auto generated service credential")
-				return err
+				return "", err
 			}
+			cred = key.Credentials
 
-			// Step 4: Assign the Access Key and Secret Key for further operation
-			opt.AccessKey = h.AccessKeyID
-			opt.SecretKey = h.SecretKeyID
-
+		} else {
+			// Use the service credential already created
+			klog.Infof("Reading the existing service credential: %s", opt.ServiceCredName)
+			cred = keys.Resources[0].Credentials
 		}
 
-		pvmclient, err := client.NewPVMClient(bxCli, opt.InstanceID, opt.InstanceName)
+		jsonString, err := json.Marshal(cred.GetProperty("cos_hmac_keys"))
 		if err != nil {
-			return err
+			return "", err
 		}
-
-		image, err := pvmclient.ImgClient.ImportImage(pvmclient.InstanceID, opt.ImageName, opt.ImageFilename, opt.Region,
-			opt.AccessKey, opt.SecretKey, opt.BucketName, strings.ToLower(opt.OsType), strings.ToLower(opt.StorageType))
+		h := struct {
+			AccessKeyID string `json:"access_key_id"`
+			SecretKeyID string `json:"secret_access_key"`
+		}{}
+		err = json.Unmarshal(jsonString, &h)
 		if err != nil {
-			return err
+			klog.Errorf("failed to unmarshal the access credentials from the auto generated service credential")
+			return "", err
 		}
 
-		klog.Infof("Importing Image %s is currently in %s state, Please check the Progress in the IBM Cloud UI\n", *image.Name, image.State)
-		return nil
-	},
+		// Step 4: Assign the Access Key and Secret Key for further operation
+		opt.AccessKey = h.AccessKeyID
+		opt.SecretKey = h.SecretKeyID
+
+	}
+
+	pvmclient, err := client.NewPVMClient(bxCli, opt.InstanceID, opt.InstanceName)
+	if err != nil {
+		return "", err
+	}
+
+	img, err := pvmclient.ImgClient.ImportImage(pvmclient.InstanceID, opt.ImageName, opt.ImageFilename, opt.Region,
+		opt.AccessKey, opt.SecretKey, opt.BucketName, strings.ToLower(opt.OsType), strings.ToLower(opt.StorageType))
+	if err != nil {
+		return "", err
+	}
+
+	klog.Infof("Importing Image %s is currently in %s state\n", *img.Name, img.State)
+
+	jobPath, err := jobs.DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	jobStore, err := jobs.NewStore(jobPath)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().Format(time.RFC3339)
+	if err := jobStore.Upsert(jobs.Record{
+		ImageID:    *img.ImageID,
+		ImageName:  *img.Name,
+		InstanceID: pvmclient.InstanceID,
+		State:      img.State,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}); err != nil {
+		return "", fmt.Errorf("failed to record import job %s: %v", *img.ImageID, err)
+	}
+
+	if !wait {
+		klog.Infof("Not waiting for the import to complete, check its progress with: pvsadm image job get %s", *img.ImageID)
+		return *img.ImageID, nil
+	}
+
+	waitOpts := image.DefaultWaitOptions()
+	waitOpts.Timeout = waitTimeout
+	waitOpts.Progress = func(i *models.Image) {
+		if outputFormat == "json" {
+			b, _ := json.Marshal(i)
+			fmt.Println(string(b))
+		} else {
+			klog.Infof("import job %s is %s", *img.ImageID, i.State)
+		}
+		_ = jobStore.Upsert(jobs.Record{
+			ImageID:    *img.ImageID,
+			ImageName:  *img.Name,
+			InstanceID: pvmclient.InstanceID,
+			State:      i.State,
+			CreatedAt:  now,
+			UpdatedAt:  time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if _, err := pvmclient.ImgClient.WaitForImport(cmd.Context(), *img.ImageID, waitOpts); err != nil {
+		return *img.ImageID, err
+	}
+
+	klog.Infof("Import job %s completed successfully", *img.ImageID)
+	return *img.ImageID, nil
 }
 
 func init() {
@@ -190,8 +339,34 @@ func init() {
 	Cmd.Flags().StringVar(&pkg.ImageCMDOptions.StorageType, "storagetype", "tier3", "Storage type, accepted values are [tier1, tier3]")
 	Cmd.Flags().StringVar(&pkg.ImageCMDOptions.ServiceCredName, "service-credential-name", "pvsadm-service-cred", "Service Credential name to be auto generated")
 
-	_ = Cmd.MarkFlagRequired("bucket")
-	_ = Cmd.MarkFlagRequired("image-name")
-	_ = Cmd.MarkFlagRequired("object-name")
-	_ = Cmd.MarkFlagRequired("region")
+	Cmd.Flags().StringVar((*string)(&sourceOpt.Provider), "source-provider", "ibmcos", "Cloud Storage provider to import the object from, accepted values are [ibmcos, aws, azure, gcs]")
+	Cmd.Flags().BoolVar(&keepStaging, "keep-staging", false, "Keep the staged COS object instead of deleting it once the import job is submitted (or, with --wait, once the wait ends)")
+
+	Cmd.Flags().StringVar(&sourceOpt.AWSAccessKeyID, "aws-access-key-id", os.Getenv("AWS_ACCESS_KEY_ID"), "AWS access key ID, used when --source-provider=aws")
+	Cmd.Flags().StringVar(&sourceOpt.AWSSecretAccessKey, "aws-secret-access-key", os.Getenv("AWS_SECRET_ACCESS_KEY"), "AWS secret access key, used when --source-provider=aws")
+	Cmd.Flags().StringVar(&sourceOpt.AWSRegion, "aws-region", "", "AWS region of the source S3 bucket, used when --source-provider=aws")
+	Cmd.Flags().StringVar(&sourceOpt.AWSBucket, "aws-bucket", "", "Source S3 bucket name, used when --source-provider=aws")
+	Cmd.Flags().StringVar(&sourceOpt.AWSObjectKey, "aws-object-key", "", "Source S3 object key, used when --source-provider=aws")
+
+	Cmd.Flags().StringVar(&sourceOpt.AzureStorageAccount, "azure-storage-account", "", "Azure storage account name, used when --source-provider=azure")
+	Cmd.Flags().StringVar(&sourceOpt.AzureSASURL, "azure-sas-url", "", "Azure blob SAS URL, used when --source-provider=azure (alternative to --azure-connection-string)")
+	Cmd.Flags().StringVar(&sourceOpt.AzureConnectionString, "azure-connection-string", "", "Azure storage account key, used with --azure-storage-account when --source-provider=azure (alternative to --azure-sas-url)")
+	Cmd.Flags().StringVar(&sourceOpt.AzureContainer, "azure-container", "", "Azure blob container name, used when --source-provider=azure")
+	Cmd.Flags().StringVar(&sourceOpt.AzureBlobName, "azure-blob-name", "", "Azure blob name, used when --source-provider=azure")
+
+	Cmd.Flags().StringVar(&sourceOpt.GCPServiceAccountJSON, "gcp-service-account-json", os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"), "Path to a GCP service-account JSON key file, used when --source-provider=gcs")
+	Cmd.Flags().StringVar(&sourceOpt.GCSBucket, "gcs-bucket", "", "Source GCS bucket name, used when --source-provider=gcs")
+	Cmd.Flags().StringVar(&sourceOpt.GCSObjectName, "gcs-object-name", "", "Source GCS object name, used when --source-provider=gcs")
+
+	Cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the import job to reach a terminal state before returning")
+	Cmd.Flags().DurationVar(&waitTimeout, "timeout", 30*time.Minute, "Maximum time to wait for the import job, used with --wait")
+	Cmd.Flags().StringVar(&outputFormat, "output", "text", "Progress output format when using --wait, accepted values are [text, json]")
+
+	Cmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "Skip the pre-flight validation of the source object")
+	Cmd.Flags().StringVar(&checksum, "sha256", "", "Expected sha256 checksum of the object, verified before the import job is submitted")
+	Cmd.Flags().StringVar(&checksumFile, "checksum-file", "", "Path to a sha256sum(1)-style file containing the expected checksum of the object")
+
+	// bucket/image-name/object-name/region are required for a single-image
+	// import but not for --manifest, so they are checked in RunE instead of
+	// being marked required here.
 }
@@ -0,0 +1,94 @@
+package _import
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"github.com/ppc64le-cloud/pvsadm/pkg/image/batch"
+)
+
+var (
+	manifestPath      string
+	maxParallel       int
+	batchRetries      int
+	batchReportFormat string
+	batchReportFile   string
+)
+
+// runManifestImport drives `pvsadm image import --manifest`: it loads the
+// manifest, fans the listed images out across a worker pool, and writes an
+// aggregate report once every import has finished or exhausted its retries.
+func runManifestImport(cmd *cobra.Command, manifestPath string) error {
+	manifest, err := batch.LoadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	importer := func(ctx context.Context, spec batch.Spec) (string, error) {
+		opt := pkg.ImageCMDOptions
+		opt.BucketName = spec.BucketName
+		opt.ImageFilename = spec.ObjectName
+		opt.ImageName = spec.ImageName
+		opt.Region = spec.Region
+		opt.InstanceID = spec.InstanceID
+		opt.InstanceName = spec.InstanceName
+		if spec.OsType != "" {
+			opt.OsType = strings.ToLower(spec.OsType)
+		}
+		if spec.StorageType != "" {
+			opt.StorageType = strings.ToLower(spec.StorageType)
+		}
+
+		return doImport(cmd, opt)
+	}
+
+	results := batch.Run(cmd.Context(), manifest, importer, batch.RunOptions{
+		MaxParallel:  maxParallel,
+		Retries:      batchRetries,
+		RetryBackoff: 10 * time.Second,
+		IsTransient:  batch.IsTransient,
+	})
+
+	report := batch.NewReport(results)
+	klog.Infof("batch import finished: %d succeeded, %d failed out of %d", report.Succeeded, report.Failed, report.Total)
+
+	out := os.Stdout
+	if batchReportFile != "" {
+		f, err := os.Create(batchReportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report file %s: %v", batchReportFile, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch batchReportFormat {
+	case "junit":
+		err = report.WriteJUnit(out)
+	default:
+		err = report.WriteJSON(out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the batch report: %v", err)
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d imports failed, see the report for details", report.Failed, report.Total)
+	}
+	return nil
+}
+
+func init() {
+	Cmd.Flags().StringVar(&manifestPath, "manifest", "", "Path to a YAML manifest listing images to import in a batch, see docs for the schema")
+	Cmd.Flags().IntVar(&maxParallel, "max-parallel", 4, "Maximum number of concurrent imports when using --manifest")
+	Cmd.Flags().IntVar(&batchRetries, "retries", 2, "Number of retries per image on transient failure when using --manifest")
+	Cmd.Flags().StringVar(&batchReportFormat, "report-format", "json", "Batch report format when using --manifest, accepted values are [json, junit]")
+	Cmd.Flags().StringVar(&batchReportFile, "report-file", "", "Write the batch report to this path instead of stdout")
+}
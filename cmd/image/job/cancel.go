@@ -0,0 +1,64 @@
+package job
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client"
+	"github.com/ppc64le-cloud/pvsadm/pkg/jobs"
+)
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <image-id>",
+	Short: "Cancel a tracked import job and remove it from the job log",
+	Long: `Cancel a tracked import job and remove it from the job log
+
+PowerVS has no dedicated cancel API for an in-flight import, so this deletes
+the (possibly partial) image resource it created and stops tracking it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		imageID := args[0]
+		apikey := pkg.Options.APIKey
+
+		path, err := jobs.DefaultPath()
+		if err != nil {
+			return err
+		}
+		store, err := jobs.NewStore(path)
+		if err != nil {
+			return err
+		}
+
+		record, found, err := store.Get(imageID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("no tracked import job found with image ID %s", imageID)
+		}
+
+		bxCli, err := client.NewClient(apikey)
+		if err != nil {
+			return err
+		}
+
+		pvmclient, err := client.NewPVMClient(bxCli, record.InstanceID, "")
+		if err != nil {
+			return err
+		}
+
+		if err := pvmclient.ImgClient.Delete(imageID); err != nil {
+			return fmt.Errorf("failed to delete the image resource for job %s, job is still tracked: %v", imageID, err)
+		}
+
+		if err := store.Remove(imageID); err != nil {
+			return err
+		}
+
+		klog.Infof("Canceled import job %s", imageID)
+		return nil
+	},
+}
@@ -0,0 +1,103 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client"
+	"github.com/ppc64le-cloud/pvsadm/pkg/jobs"
+)
+
+var (
+	getOutputFormat string
+	getNoRefresh    bool
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <image-id>",
+	Short: "Show the current state of a tracked import job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := jobs.DefaultPath()
+		if err != nil {
+			return err
+		}
+		store, err := jobs.NewStore(path)
+		if err != nil {
+			return err
+		}
+
+		record, found, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("no tracked import job found with image ID %s", args[0])
+		}
+
+		// The normal async path (`image import` without --wait) only ever
+		// Upserts the job once, at "queued". Without polling here, `job get`
+		// would show that frozen state forever instead of the job's actual
+		// progress.
+		if !getNoRefresh {
+			if refreshed, err := refreshRecord(record); err != nil {
+				klog.Warningf("failed to refresh the live state of job %s, showing the last known state: %v", args[0], err)
+			} else {
+				record = refreshed
+				if err := store.Upsert(record); err != nil {
+					klog.Warningf("failed to persist the refreshed state of job %s: %v", args[0], err)
+				}
+			}
+		}
+
+		if getOutputFormat == "json" {
+			b, err := json.MarshalIndent(record, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		fmt.Printf("Image ID:    %s\n", record.ImageID)
+		fmt.Printf("Image Name:  %s\n", record.ImageName)
+		fmt.Printf("Instance ID: %s\n", record.InstanceID)
+		fmt.Printf("State:       %s\n", record.State)
+		fmt.Printf("Created At:  %s\n", record.CreatedAt)
+		fmt.Printf("Updated At:  %s\n", record.UpdatedAt)
+		return nil
+	},
+}
+
+// refreshRecord fetches the current state of record's image from PowerVS and
+// returns the record updated to reflect it.
+func refreshRecord(record jobs.Record) (jobs.Record, error) {
+	bxCli, err := client.NewClient(pkg.Options.APIKey)
+	if err != nil {
+		return record, err
+	}
+
+	pvmclient, err := client.NewPVMClient(bxCli, record.InstanceID, "")
+	if err != nil {
+		return record, err
+	}
+
+	img, err := pvmclient.ImgClient.Get(record.ImageID)
+	if err != nil {
+		return record, fmt.Errorf("failed to get the status of image %s: %v", record.ImageID, err)
+	}
+
+	record.State = img.State
+	record.UpdatedAt = time.Now().Format(time.RFC3339)
+	return record, nil
+}
+
+func init() {
+	getCmd.Flags().StringVar(&getOutputFormat, "output", "text", "Output format, accepted values are [text, json]")
+	getCmd.Flags().BoolVar(&getNoRefresh, "no-refresh", false, "Show the last known state from the job log without polling PowerVS for the current state")
+}
@@ -0,0 +1,21 @@
+// Package job implements `pvsadm image job`, a small set of commands to
+// list, inspect, and cancel import jobs tracked in the local job log
+// (~/.pvsadm/jobs.json), so progress can be resumed across pvsadm
+// invocations.
+package job
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "job",
+	Short: "Manage tracked PowerVS image import jobs",
+	Long:  `Manage PowerVS image import jobs tracked in the local job log (~/.pvsadm/jobs.json)`,
+}
+
+func init() {
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(getCmd)
+	Cmd.AddCommand(cancelCmd)
+}
@@ -0,0 +1,111 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client"
+	"github.com/ppc64le-cloud/pvsadm/pkg/jobs"
+)
+
+var (
+	listOutputFormat string
+	listRefresh      bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tracked import jobs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := jobs.DefaultPath()
+		if err != nil {
+			return err
+		}
+		store, err := jobs.NewStore(path)
+		if err != nil {
+			return err
+		}
+
+		records, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		if listRefresh {
+			records = refreshRecords(store, records)
+		}
+
+		if listOutputFormat == "json" {
+			b, err := json.MarshalIndent(records, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "IMAGE ID\tIMAGE NAME\tINSTANCE ID\tSTATE\tUPDATED AT")
+		for _, r := range records {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.ImageID, r.ImageName, r.InstanceID, r.State, r.UpdatedAt)
+		}
+		return w.Flush()
+	},
+}
+
+// refreshRecords polls PowerVS for the current state of every record and
+// persists the refreshed state, reusing one pvmclient per InstanceID since
+// records are commonly tracked across the same handful of workspaces. A
+// record that fails to refresh keeps its last known state.
+func refreshRecords(store *jobs.Store, records []jobs.Record) []jobs.Record {
+	bxCli, err := client.NewClient(pkg.Options.APIKey)
+	if err != nil {
+		klog.Warningf("failed to refresh job states: %v", err)
+		return records
+	}
+
+	pvmclients := map[string]*client.PVMClient{}
+	refreshed := make([]jobs.Record, len(records))
+	for i, r := range records {
+		refreshed[i] = r
+
+		pvmclient, ok := pvmclients[r.InstanceID]
+		if !ok {
+			pvmclient, err = client.NewPVMClient(bxCli, r.InstanceID, "")
+			if err != nil {
+				klog.Warningf("failed to refresh jobs on instance %s: %v", r.InstanceID, err)
+				pvmclients[r.InstanceID] = nil
+				continue
+			}
+			pvmclients[r.InstanceID] = pvmclient
+		}
+		if pvmclient == nil {
+			continue
+		}
+
+		img, err := pvmclient.ImgClient.Get(r.ImageID)
+		if err != nil {
+			klog.Warningf("failed to refresh the state of job %s: %v", r.ImageID, err)
+			continue
+		}
+
+		r.State = img.State
+		r.UpdatedAt = time.Now().Format(time.RFC3339)
+		refreshed[i] = r
+		if err := store.Upsert(r); err != nil {
+			klog.Warningf("failed to persist the refreshed state of job %s: %v", r.ImageID, err)
+		}
+	}
+	return refreshed
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listOutputFormat, "output", "text", "Output format, accepted values are [text, json]")
+	listCmd.Flags().BoolVar(&listRefresh, "refresh", false, "Poll PowerVS for the current state of every tracked job before listing")
+}
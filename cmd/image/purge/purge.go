@@ -0,0 +1,161 @@
+// Package purge implements `pvsadm image purge`, a policy-driven cleanup
+// command built on top of Client.GetAllPurgeable: it evaluates a YAML
+// policy of name/age rules against the images in a workspace, optionally
+// skips any image still attached to a running PVM instance, and only
+// deletes for real once the operator has confirmed (or passed --yes), so
+// the same policy file can safely back a scheduled cleanup cronjob.
+package purge
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client"
+	"github.com/ppc64le-cloud/pvsadm/pkg/image/purge"
+)
+
+var opts struct {
+	instanceName string
+	instanceID   string
+	policyPath   string
+	dryRun       bool
+	yes          bool
+	excludeInUse bool
+	reportFile   string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete images that match a purge policy",
+	Long: `Delete images that match a purge policy
+
+pvsadm image purge --help for information
+
+Each rule in the policy file names a regex the image name must match, an
+age window it must fall in, and how many of the newest matches sharing a
+name prefix to always keep. Nothing is deleted unless --dry-run=false and
+either --yes is passed or the operator confirms interactively.
+
+Example policy.yaml:
+
+rules:
+  - nameRegex: "^ci-.*"
+    minAge: 168h
+    keepLastNPerPrefix: 3
+
+Examples:
+
+# see what would be deleted, without deleting anything
+pvsadm image purge -n upstream-core-lon04 --policy policy.yaml
+
+# actually delete, skipping images still attached to an instance
+pvsadm image purge -n upstream-core-lon04 --policy policy.yaml --dry-run=false --exclude-in-use --yes
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := purge.LoadPolicy(opts.policyPath)
+		if err != nil {
+			return err
+		}
+
+		apikey := pkg.Options.APIKey
+		bxCli, err := client.NewClient(apikey)
+		if err != nil {
+			return err
+		}
+
+		pvmclient, err := client.NewPVMClient(bxCli, opts.instanceID, opts.instanceName)
+		if err != nil {
+			return err
+		}
+
+		var inUse map[string]bool
+		if opts.excludeInUse {
+			inUse, err = pvmclient.InstanceClient.ImagesInUse()
+			if err != nil {
+				return fmt.Errorf("failed to list images in use: %v", err)
+			}
+		}
+
+		decisions, err := purge.Plan(policy, pvmclient.ImgClient.GetAllPurgeable, inUse)
+		if err != nil {
+			return err
+		}
+
+		report := purge.NewReport(decisions, opts.dryRun)
+
+		out := os.Stdout
+		if opts.reportFile != "" {
+			f, err := os.Create(opts.reportFile)
+			if err != nil {
+				return fmt.Errorf("failed to create report file %s: %v", opts.reportFile, err)
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := report.WriteJSON(out); err != nil {
+			return fmt.Errorf("failed to write the purge report: %v", err)
+		}
+
+		if opts.dryRun {
+			klog.Infof("dry-run: %d of %d images would be deleted, rerun with --dry-run=false to delete them", report.Deleted, report.Total)
+			return nil
+		}
+
+		if report.Deleted == 0 {
+			klog.Infof("no images to delete under the policy, nothing to do")
+			return nil
+		}
+
+		if !opts.yes && !confirm(report.Deleted) {
+			klog.Infof("aborted, no images were deleted")
+			return nil
+		}
+
+		var failed int
+		for _, d := range decisions {
+			if d.Kept {
+				continue
+			}
+			if err := pvmclient.ImgClient.Delete(d.ImageID); err != nil {
+				failed++
+				klog.Errorf("failed to delete image %s (%s): %v", d.Name, d.ImageID, err)
+				continue
+			}
+			klog.Infof("deleted image %s (%s): %s", d.Name, d.ImageID, d.Reason)
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to delete %d of %d images, see above for details", failed, report.Deleted)
+		}
+
+		klog.Infof("deleted %d of %d images", report.Deleted, report.Total)
+		return nil
+	},
+}
+
+// confirm prompts the operator to type "yes" before deleting n images.
+func confirm(n int) bool {
+	fmt.Printf("About to permanently delete %d image(s). Type 'yes' to continue: ", n)
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return false
+	}
+	return strings.TrimSpace(scanner.Text()) == "yes"
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&opts.instanceName, "instance-name", "n", "", "Instance name of the PowerVS workspace to purge images from")
+	Cmd.Flags().StringVarP(&opts.instanceID, "instance-id", "i", "", "Instance ID of the PowerVS workspace to purge images from")
+	Cmd.Flags().StringVar(&opts.policyPath, "policy", "", "Path to a YAML policy file listing purge rules")
+	Cmd.Flags().BoolVar(&opts.dryRun, "dry-run", true, "Report what would be deleted without deleting anything")
+	Cmd.Flags().BoolVar(&opts.yes, "yes", false, "Delete without an interactive confirmation prompt, for non-interactive/cron use")
+	Cmd.Flags().BoolVar(&opts.excludeInUse, "exclude-in-use", false, "Never delete an image currently attached to a PVM instance")
+	Cmd.Flags().StringVar(&opts.reportFile, "report-file", "", "Write the purge report to this path instead of stdout")
+
+	_ = Cmd.MarkFlagRequired("policy")
+}
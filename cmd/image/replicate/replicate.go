@@ -0,0 +1,271 @@
+// Package replicate implements `pvsadm image replicate`, the PowerVS analog
+// of the multi-region publish workflows found in other image tooling: it
+// takes an image that already exists in one PowerVS workspace and fans it
+// out to a list of target workspaces, each potentially in a different
+// region, with a single command instead of scripting N imports.
+package replicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client/image"
+	"github.com/ppc64le-cloud/pvsadm/pkg/image/replicate"
+	"github.com/ppc64le-cloud/pvsadm/pkg/jobs"
+)
+
+var opts struct {
+	cosInstance     string
+	sourceInstance  string
+	sourceRegion    string
+	imageID         string
+	targetInstances string
+	targetRegions   string
+	bucketPrefix    string
+	accessKey       string
+	secretKey       string
+	osType          string
+	storageType     string
+	maxParallel     int
+	wait            bool
+	waitTimeout     time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "replicate",
+	Short: "Replicate an existing PowerVS image into one or more target workspaces",
+	Long: `Replicate an existing PowerVS image into one or more target workspaces
+
+pvsadm image replicate exports the source image's OVA into a staging COS
+bucket, copies that object into a staging bucket in every target region, and
+imports it into every target workspace in parallel. Each target's progress
+is tracked the same way "pvsadm image import" tracks a single import, so
+"pvsadm image job list" shows every in-flight replica.
+
+Examples:
+
+# replicate an image from one workspace into two others
+pvsadm image replicate --source-instance wdc06-core --source-region us-east --image rhel-83-golden \
+  --target-instances lon04-core,syd04-core --target-regions lon,syd \
+  --cos-instance pvsadm-cos --accesskey <ACCESSKEY> --secretkey <SECRETKEY>
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetInstances := splitNonEmpty(opts.targetInstances)
+		targetRegions := splitNonEmpty(opts.targetRegions)
+		if len(targetInstances) == 0 {
+			return fmt.Errorf("--target-instances must list at least one target workspace")
+		}
+		if len(targetInstances) != len(targetRegions) {
+			return fmt.Errorf("--target-instances lists %d workspaces but --target-regions lists %d regions, they must pair up 1:1", len(targetInstances), len(targetRegions))
+		}
+
+		apikey := pkg.Options.APIKey
+		bxCli, err := client.NewClient(apikey)
+		if err != nil {
+			return err
+		}
+
+		sourcePVM, err := client.NewPVMClient(bxCli, "", opts.sourceInstance)
+		if err != nil {
+			return err
+		}
+
+		img, err := resolveImage(sourcePVM.ImgClient, opts.imageID)
+		if err != nil {
+			return err
+		}
+
+		sourceBucket := fmt.Sprintf("%s-%s", opts.bucketPrefix, opts.sourceRegion)
+		sourceS3, err := client.NewS3Client(bxCli, opts.cosInstance, opts.sourceRegion)
+		if err != nil {
+			return err
+		}
+		if err := ensureBucket(sourceS3, sourceBucket, opts.sourceRegion); err != nil {
+			return err
+		}
+
+		objectName := *img.Name + ".ova.gz"
+		klog.Infof("exporting image %s to %s/%s", *img.ImageID, sourceBucket, objectName)
+		jobRef, err := sourcePVM.ImgClient.ExportImage(*img.ImageID, sourceBucket, opts.accessKey, opts.secretKey, opts.sourceRegion)
+		if err != nil {
+			return err
+		}
+		if err := sourcePVM.ImgClient.WaitForJob(cmd.Context(), *jobRef.ID, image.DefaultWaitOptions()); err != nil {
+			return fmt.Errorf("export of image %s did not complete: %v", *img.ImageID, err)
+		}
+
+		jobPath, err := jobs.DefaultPath()
+		if err != nil {
+			return err
+		}
+		jobStore, err := jobs.NewStore(jobPath)
+		if err != nil {
+			return err
+		}
+
+		targets := make([]replicate.Target, len(targetInstances))
+		for i := range targetInstances {
+			targets[i] = replicate.Target{
+				InstanceName: targetInstances[i],
+				Region:       targetRegions[i],
+				BucketName:   fmt.Sprintf("%s-%s", opts.bucketPrefix, targetRegions[i]),
+			}
+		}
+
+		importer := func(ctx context.Context, target replicate.Target) (string, error) {
+			targetS3, err := client.NewS3Client(bxCli, opts.cosInstance, target.Region)
+			if err != nil {
+				return "", err
+			}
+			if err := ensureBucket(targetS3, target.BucketName, target.Region); err != nil {
+				return "", err
+			}
+
+			uploader := s3manager.NewUploaderWithClient(targetS3.S3Session)
+			if _, err := image.Stage(ctx, uploader, image.StageOptions{
+				Provider:        image.SourceIBMCOS,
+				IBMCOSClient:    sourceS3.S3Session,
+				IBMCOSBucket:    sourceBucket,
+				IBMCOSObjectKey: objectName,
+				DestBucketName:  target.BucketName,
+				DestObjectName:  objectName,
+			}); err != nil {
+				return "", fmt.Errorf("failed to copy %s into %s/%s: %v", objectName, target.Region, target.BucketName, err)
+			}
+
+			targetPVM, err := client.NewPVMClient(bxCli, target.InstanceID, target.InstanceName)
+			if err != nil {
+				return "", err
+			}
+
+			targetImg, err := targetPVM.ImgClient.ImportImage(targetPVM.InstanceID, *img.Name, objectName, target.Region,
+				opts.accessKey, opts.secretKey, target.BucketName, strings.ToLower(opts.osType), strings.ToLower(opts.storageType))
+			if err != nil {
+				return "", err
+			}
+
+			now := time.Now().Format(time.RFC3339)
+			if err := jobStore.Upsert(jobs.Record{
+				ImageID:    *targetImg.ImageID,
+				ImageName:  *targetImg.Name,
+				InstanceID: targetPVM.InstanceID,
+				State:      targetImg.State,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}); err != nil {
+				return "", fmt.Errorf("failed to record replicate job %s: %v", *targetImg.ImageID, err)
+			}
+
+			if opts.wait {
+				waitOpts := image.DefaultWaitOptions()
+				waitOpts.Timeout = opts.waitTimeout
+				if _, err := targetPVM.ImgClient.WaitForImport(ctx, *targetImg.ImageID, waitOpts); err != nil {
+					return *targetImg.ImageID, err
+				}
+			}
+
+			return *targetImg.ImageID, nil
+		}
+
+		results := replicate.Run(cmd.Context(), targets, importer, replicate.RunOptions{MaxParallel: opts.maxParallel})
+
+		var failed int
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+				klog.Errorf("%s (%s): %v", r.Target.InstanceName, r.Target.Region, r.Err)
+				continue
+			}
+			klog.Infof("%s (%s): imported as %s", r.Target.InstanceName, r.Target.Region, r.ImageID)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d targets failed, see above for details", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// splitNonEmpty splits a comma-separated flag value, dropping empty entries
+// so a trailing comma or blank flag does not produce a spurious target.
+func splitNonEmpty(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func init() {
+	Cmd.Flags().StringVar(&opts.sourceInstance, "source-instance", "", "Name of the PowerVS workspace the image currently exists in")
+	Cmd.Flags().StringVar(&opts.sourceRegion, "source-region", "", "COS region to stage the export in, usually the source workspace's own region")
+	Cmd.Flags().StringVar(&opts.imageID, "image", "", "ID or name of the image to replicate")
+	Cmd.Flags().StringVar(&opts.targetInstances, "target-instances", "", "Comma-separated list of target PowerVS workspace names")
+	Cmd.Flags().StringVar(&opts.targetRegions, "target-regions", "", "Comma-separated list of COS regions, one per entry in --target-instances")
+	Cmd.Flags().StringVar(&opts.cosInstance, "cos-instance", "", "Name of the COS service instance holding the staging buckets in every region")
+	Cmd.Flags().StringVar(&opts.bucketPrefix, "bucket-prefix", "pvsadm-replicate", "Prefix used to name (or create) the per-region staging buckets, as <prefix>-<region>")
+	Cmd.Flags().StringVar(&opts.accessKey, "accesskey", "", "Cloud Storage access key, valid for every region involved")
+	Cmd.Flags().StringVar(&opts.secretKey, "secretkey", "", "Cloud Storage secret key, valid for every region involved")
+	Cmd.Flags().StringVar(&opts.osType, "ostype", "redhat", "Image OS Type, accepted values are [aix, ibmi, redhat, sles]")
+	Cmd.Flags().StringVar(&opts.storageType, "storagetype", "tier3", "Storage type, accepted values are [tier1, tier3]")
+	Cmd.Flags().IntVar(&opts.maxParallel, "max-parallel", 4, "Maximum number of concurrent target imports")
+	Cmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait for every target import to reach a terminal state before returning")
+	Cmd.Flags().DurationVar(&opts.waitTimeout, "timeout", 30*time.Minute, "Maximum time to wait per target import, used with --wait")
+
+	for _, flag := range []string{"source-instance", "source-region", "image", "target-instances", "target-regions", "cos-instance", "accesskey", "secretkey"} {
+		_ = Cmd.MarkFlagRequired(flag)
+	}
+}
+
+// ensureBucket makes sure bucket exists in s3client's region, creating it if
+// this is the first time it is used as a replication staging area.
+func ensureBucket(s3client *client.S3Client, bucket, region string) error {
+	buckets, err := s3client.S3Session.ListBuckets(nil)
+	if err != nil {
+		return fmt.Errorf("failed to list buckets in %s: %v", region, err)
+	}
+	for _, b := range buckets.Buckets {
+		if *b.Name == bucket {
+			return nil
+		}
+	}
+
+	klog.Infof("staging bucket %s does not exist in %s, creating it", bucket, region)
+	_, err = s3client.S3Session.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return fmt.Errorf("failed to create staging bucket %s in %s: %v", bucket, region, err)
+	}
+	return nil
+}
+
+// resolveImage looks up an image by ID, falling back to a name match against
+// every image in the source workspace so --image can take either.
+func resolveImage(imgClient *image.Client, idOrName string) (*models.Image, error) {
+	if img, err := imgClient.Get(idOrName); err == nil {
+		return img, nil
+	}
+
+	images, err := imgClient.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up image %s: %v", idOrName, err)
+	}
+	for _, img := range images.Images {
+		if img.Name != nil && *img.Name == idOrName {
+			return imgClient.Get(*img.ImageID)
+		}
+	}
+	return nil, fmt.Errorf("no image named or with ID %s found in the source workspace", idOrName)
+}
@@ -0,0 +1,101 @@
+// Package client builds the IBM Cloud API clients pvsadm commands share: an
+// IAM-authenticated session (Client), and the PowerVS-workspace-scoped
+// wrappers built on top of it (PVMClient) for importing/exporting/deleting
+// images and inspecting which images back a running PVM instance.
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM/go-sdk-core/v4/core"
+	rcv2 "github.com/IBM/platform-services-go-sdk/resourcecontrollerv2"
+
+	"github.com/ppc64le-cloud/pvsadm/pkg/client/image"
+	"github.com/ppc64le-cloud/pvsadm/pkg/client/instance"
+)
+
+// Client holds the IBM Cloud API key pvsadm authenticates with. The
+// constructors in this package each derive the scoped authenticator/session
+// they need from it.
+type Client struct {
+	APIKey string
+}
+
+// NewClient returns a Client authenticated as apikey.
+func NewClient(apikey string) (*Client, error) {
+	if apikey == "" {
+		return nil, fmt.Errorf("missing IBM Cloud API key, set IBMCLOUD_API_KEY or pass --api-key")
+	}
+	return &Client{APIKey: apikey}, nil
+}
+
+// PVMClient groups the PowerVS-workspace-scoped clients a single instance
+// needs: importing/exporting/deleting images (ImgClient), and looking up
+// which images currently back a running PVM instance (InstanceClient).
+type PVMClient struct {
+	InstanceID     string
+	ImgClient      *image.Client
+	InstanceClient *instance.Client
+}
+
+// NewPVMClient resolves instanceID or instanceName, whichever is set, to a
+// PowerVS workspace and returns the clients scoped to it.
+func NewPVMClient(c *Client, instanceID, instanceName string) (*PVMClient, error) {
+	if instanceID == "" && instanceName == "" {
+		return nil, fmt.Errorf("one of instance ID or instance name is required")
+	}
+
+	auth, err := core.NewIamAuthenticator(c.APIKey, "", "", "", false, nil)
+	if err != nil {
+		return nil, err
+	}
+	resourceController, err := rcv2.NewResourceControllerV2(&rcv2.ResourceControllerV2Options{Authenticator: auth})
+	if err != nil {
+		return nil, err
+	}
+
+	instances, _, err := resourceController.ListResourceInstances(
+		resourceController.NewListResourceInstancesOptions().SetType("service_instance"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the PowerVS workspaces: %v", err)
+	}
+
+	var workspace *rcv2.ResourceInstance
+	for i := range instances.Resources {
+		r := &instances.Resources[i]
+		if r.Crn == nil || !strings.Contains(*r.Crn, "power-iaas") {
+			continue
+		}
+		if (instanceID != "" && r.GUID != nil && *r.GUID == instanceID) ||
+			(instanceName != "" && r.Name != nil && *r.Name == instanceName) {
+			workspace = r
+			break
+		}
+	}
+	if workspace == nil {
+		return nil, fmt.Errorf("failed to find the PowerVS workspace %s%s", instanceID, instanceName)
+	}
+
+	// A PowerVS workspace CRN looks like
+	// crn:v1:bluemix:public:power-iaas:<zone>:a/<account>:<guid>::, so the
+	// zone ibmpisession needs to authenticate against it is the 6th segment.
+	crnParts := strings.Split(*workspace.Crn, ":")
+	if len(crnParts) < 6 {
+		return nil, fmt.Errorf("unexpected CRN format for PowerVS workspace %s: %s", *workspace.GUID, *workspace.Crn)
+	}
+	zone := crnParts[5]
+
+	sess, err := ibmpisession.New(c.APIKey, zone, false, 90*time.Second, *workspace.AccountID, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the PowerVS session for workspace %s: %v", *workspace.GUID, err)
+	}
+
+	return &PVMClient{
+		InstanceID:     *workspace.GUID,
+		ImgClient:      image.NewClient(sess, *workspace.GUID),
+		InstanceClient: instance.NewClient(sess, *workspace.GUID),
+	}, nil
+}
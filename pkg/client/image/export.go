@@ -0,0 +1,85 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_images"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_jobs"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"k8s.io/klog/v2"
+)
+
+// ExportImage submits a PowerVS job to export imageID into the given COS
+// bucket/region, so it can be imported again into another PowerVS workspace.
+// Export, like import, is asynchronous; callers should pass the returned
+// job ID to WaitForJob before relying on the bucket contents.
+func (c *Client) ExportImage(imageID, bucketName, accessKey, secretKey, region string) (*models.JobReference, error) {
+	body := models.ExportImage{
+		BucketName: &bucketName,
+		AccessKey:  accessKey,
+		SecretKey:  secretKey,
+		Region:     &region,
+	}
+
+	params := p_cloud_images.NewPcloudCloudinstancesImagesExportPostParamsWithTimeout(pkg.TIMEOUT).
+		WithCloudInstanceID(c.instanceID).WithImageID(imageID).WithBody(&body)
+	resp, err := c.session.Power.PCloudImages.PcloudCloudinstancesImagesExportPost(params, ibmpisession.NewAuth(c.session, c.instanceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start export of image %s: %v", imageID, err)
+	}
+
+	klog.Infof("export of image %s to %s/%s started, job ID %s", imageID, region, bucketName, *resp.Payload.ID)
+	return resp.Payload, nil
+}
+
+// WaitForJob polls a PowerVS job, such as the one returned by ExportImage,
+// until it reaches a terminal state.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, opts WaitOptions) error {
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = DefaultWaitOptions().InitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = DefaultWaitOptions().MaxInterval
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := opts.InitialInterval
+	for {
+		params := p_cloud_jobs.NewPcloudCloudinstancesJobsGetParamsWithTimeout(pkg.TIMEOUT).
+			WithCloudInstanceID(c.instanceID).WithJobID(jobID)
+		resp, err := c.session.Power.PCloudJobs.PcloudCloudinstancesJobsGet(params, ibmpisession.NewAuth(c.session, c.instanceID))
+		if err != nil {
+			return fmt.Errorf("failed to get the status of job %s: %v", jobID, err)
+		}
+
+		status := resp.Payload.Status
+		klog.V(1).Infof("job %s is %s, polling again in %s", jobID, status.State, interval)
+
+		switch status.State {
+		case "completed":
+			return nil
+		case "failed", "error":
+			return fmt.Errorf("job %s failed: %s", jobID, status.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for job %s to complete, last state: %s", jobID, status.State)
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_images"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	"github.com/ppc64le-cloud/pvsadm/pkg"
+	"k8s.io/klog/v2"
+)
+
+// Job states as reported by PcloudCloudinstancesImagesGet. These mirror the
+// states documented for the PowerVS image import job.
+const (
+	JobStateQueued     = "queued"
+	JobStateProcessing = "processing"
+	JobStateActive     = "active"
+	JobStateFailed     = "failed"
+)
+
+// WaitOptions controls how WaitForImport polls the PowerVS API.
+type WaitOptions struct {
+	// Timeout bounds the total time spent waiting. Zero means no timeout.
+	Timeout time.Duration
+	// InitialInterval is the delay after the first poll, which is made
+	// immediately, and the starting point for the exponential backoff
+	// between subsequent polls.
+	InitialInterval time.Duration
+	// MaxInterval caps how large the backoff is allowed to grow.
+	MaxInterval time.Duration
+	// Progress, if set, is called after every poll with the latest image
+	// state so callers can stream progress to stdout or a JSON encoder.
+	Progress func(image *models.Image)
+}
+
+// DefaultWaitOptions returns the backoff schedule used when the caller does
+// not customize WaitOptions.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Timeout:         30 * time.Minute,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     30 * time.Second,
+	}
+}
+
+// WaitForImport polls the import job for imageID until it reaches a terminal
+// state (active or failed), the context is canceled, or opts.Timeout elapses.
+func (c *Client) WaitForImport(ctx context.Context, imageID string, opts WaitOptions) (*models.Image, error) {
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = DefaultWaitOptions().InitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = DefaultWaitOptions().MaxInterval
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := opts.InitialInterval
+	for {
+		params := p_cloud_images.NewPcloudCloudinstancesImagesGetParamsWithTimeout(pkg.TIMEOUT).
+			WithCloudInstanceID(c.instanceID).WithImageID(imageID)
+		resp, err := c.session.Power.PCloudImages.PcloudCloudinstancesImagesGet(params, ibmpisession.NewAuth(c.session, c.instanceID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the status of import job %s: %v", imageID, err)
+		}
+
+		image := resp.Payload
+		if opts.Progress != nil {
+			opts.Progress(image)
+		}
+
+		switch image.State {
+		case JobStateActive:
+			return image, nil
+		case JobStateFailed:
+			return image, fmt.Errorf("import job %s failed", imageID)
+		}
+
+		klog.V(1).Infof("import job %s is %s, polling again in %s", imageID, image.State, interval)
+
+		select {
+		case <-ctx.Done():
+			return image, fmt.Errorf("timed out waiting for import job %s to complete, last state: %s", imageID, image.State)
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awssession "github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"google.golang.org/api/option"
+	"k8s.io/klog/v2"
+)
+
+// SourceProvider identifies where the object to import currently lives.
+type SourceProvider string
+
+const (
+	SourceIBMCOS SourceProvider = "ibmcos"
+	SourceAWS    SourceProvider = "aws"
+	SourceAzure  SourceProvider = "azure"
+	SourceGCS    SourceProvider = "gcs"
+)
+
+// S3Getter is the subset of the S3 client Stage needs to read a source
+// object that already lives in IBM COS, e.g. a bucket in another region
+// during image replication.
+type S3Getter interface {
+	GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error)
+}
+
+// StageOptions carries the provider-specific credentials needed to read the
+// source object. Only the fields relevant to SourceProvider need to be set.
+type StageOptions struct {
+	Provider SourceProvider
+
+	// IBM COS (used when replicating an image already staged in one COS
+	// bucket into another, possibly in a different region)
+	IBMCOSClient    S3Getter
+	IBMCOSBucket    string
+	IBMCOSObjectKey string
+
+	// AWS S3
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSRegion          string
+	AWSBucket          string
+	AWSObjectKey       string
+
+	// Azure Blob Storage. Either AzureSASURL on its own, or
+	// AzureStorageAccount+AzureConnectionString (the account's shared key,
+	// not a full connection string) together with AzureContainer/AzureBlobName.
+	AzureStorageAccount   string
+	AzureSASURL           string
+	AzureConnectionString string
+	AzureContainer        string
+	AzureBlobName         string
+
+	// Google Cloud Storage
+	GCPServiceAccountJSON string
+	GCSBucket             string
+	GCSObjectName         string
+
+	// Destination staging bucket in IBM COS.
+	DestBucketName string
+	DestObjectName string
+
+	// KeepStaging records whether the staged object is to be kept in IBM COS
+	// after the import job is submitted. Stage does not read it and never
+	// deletes the staged object itself.
+	KeepStaging bool
+}
+
+// Stage streams the source object referenced by opts into the given IBM COS
+// bucket using a multipart upload, so that non-IBM sources can be imported
+// the same way a native COS object is. It returns the object name written to
+// destination bucket.
+func Stage(ctx context.Context, s3client *s3manager.Uploader, opts StageOptions) (string, error) {
+	reader, closer, err := openSource(ctx, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to open source object from %s: %v", opts.Provider, err)
+	}
+	defer closer()
+
+	klog.Infof("staging %s object into IBM COS bucket %s as %s", opts.Provider, opts.DestBucketName, opts.DestObjectName)
+	_, err = s3client.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(opts.DestBucketName),
+		Key:    aws.String(opts.DestObjectName),
+		Body:   reader,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to stage object into IBM COS: %v", err)
+	}
+
+	return opts.DestObjectName, nil
+}
+
+// openSource returns a reader over the source object for the configured
+// provider, along with a function to release any underlying clients.
+func openSource(ctx context.Context, opts StageOptions) (io.Reader, func(), error) {
+	switch opts.Provider {
+	case SourceIBMCOS:
+		if opts.IBMCOSClient == nil {
+			return nil, func() {}, fmt.Errorf("no IBM COS client configured to read %s/%s", opts.IBMCOSBucket, opts.IBMCOSObjectKey)
+		}
+		out, err := opts.IBMCOSClient.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(opts.IBMCOSBucket),
+			Key:    aws.String(opts.IBMCOSObjectKey),
+		})
+		if err != nil {
+			return nil, func() {}, err
+		}
+		return out.Body, func() { _ = out.Body.Close() }, nil
+
+	case SourceAWS:
+		sess, err := awssession.NewSession(&aws.Config{
+			Region:      aws.String(opts.AWSRegion),
+			Credentials: credentials.NewStaticCredentials(opts.AWSAccessKeyID, opts.AWSSecretAccessKey, ""),
+		})
+		if err != nil {
+			return nil, func() {}, err
+		}
+		out, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(opts.AWSBucket),
+			Key:    aws.String(opts.AWSObjectKey),
+		})
+		if err != nil {
+			return nil, func() {}, err
+		}
+		return out.Body, func() { _ = out.Body.Close() }, nil
+
+	case SourceAzure:
+		var (
+			credential azblob.Credential
+			u          string
+		)
+		if opts.AzureSASURL != "" {
+			// The SAS URL already carries its own signature, so the pipeline
+			// itself needs no credential.
+			credential = azblob.NewAnonymousCredential()
+			u = opts.AzureSASURL
+		} else {
+			c, err := azblob.NewSharedKeyCredential(opts.AzureStorageAccount, opts.AzureConnectionString)
+			if err != nil {
+				return nil, func() {}, fmt.Errorf("invalid --azure-storage-account/--azure-connection-string: %v", err)
+			}
+			credential = c
+			u = fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", opts.AzureStorageAccount, opts.AzureContainer, opts.AzureBlobName)
+		}
+		blobURL, err := azblob.NewBlobURLFromURL(u, azblob.NewPipeline(credential, azblob.PipelineOptions{}))
+		if err != nil {
+			return nil, func() {}, err
+		}
+		resp, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+		if err != nil {
+			return nil, func() {}, err
+		}
+		body := resp.Body(azblob.RetryReaderOptions{})
+		return body, func() { _ = body.Close() }, nil
+
+	case SourceGCS:
+		client, err := storage.NewClient(ctx, option.WithCredentialsFile(opts.GCPServiceAccountJSON))
+		if err != nil {
+			return nil, func() {}, err
+		}
+		rc, err := client.Bucket(opts.GCSBucket).Object(opts.GCSObjectName).NewReader(ctx)
+		if err != nil {
+			_ = client.Close()
+			return nil, func() {}, err
+		}
+		return rc, func() { _ = rc.Close(); _ = client.Close() }, nil
+
+	default:
+		return nil, func() {}, fmt.Errorf("unsupported source provider: %s", opts.Provider)
+	}
+}
@@ -0,0 +1,45 @@
+// Package instance wraps the PowerVS PVM instance API, so other packages
+// can find out which images are currently backing a running instance
+// without reaching into the IBM-Cloud/power-go-client client directly.
+package instance
+
+import (
+	"github.com/IBM-Cloud/power-go-client/clients/instance"
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+)
+
+type Client struct {
+	client     *instance.IBMPIInstanceClient
+	instanceID string
+}
+
+func NewClient(sess *ibmpisession.IBMPISession, powerinstanceid string) *Client {
+	return &Client{
+		client:     instance.NewIBMPIInstanceClient(sess, powerinstanceid),
+		instanceID: powerinstanceid,
+	}
+}
+
+func (c *Client) GetAll() (*models.PVMInstances, error) {
+	return c.client.GetAll(c.instanceID)
+}
+
+// ImagesInUse returns the set of image IDs that currently back a PVM
+// instance in this PowerVS workspace, so callers like `pvsadm image purge
+// --exclude-in-use` can skip deleting an image a running instance was
+// booted from.
+func (c *Client) ImagesInUse() (map[string]bool, error) {
+	instances, err := c.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := map[string]bool{}
+	for _, pvm := range instances.PvmInstances {
+		if pvm.ImageID != "" {
+			inUse[pvm.ImageID] = true
+		}
+	}
+	return inUse, nil
+}
@@ -0,0 +1,56 @@
+// Package batch drives many PowerVS image imports concurrently from a single
+// manifest file, so a CI pipeline can seed a number of PowerVS workspaces
+// from the same COS bucket with one pvsadm invocation.
+package batch
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Spec describes a single image to import as part of a batch run.
+type Spec struct {
+	BucketName   string `json:"bucket"`
+	ObjectName   string `json:"objectName"`
+	ImageName    string `json:"imageName"`
+	Region       string `json:"region"`
+	OsType       string `json:"osType"`
+	StorageType  string `json:"storageType"`
+	InstanceID   string `json:"instanceID,omitempty"`
+	InstanceName string `json:"instanceName,omitempty"`
+}
+
+// Manifest is the top-level document read from --manifest.
+type Manifest struct {
+	Images []Spec `json:"images"`
+}
+
+// LoadManifest reads and validates the manifest file at path.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %v", path, err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", path, err)
+	}
+
+	if len(m.Images) == 0 {
+		return nil, fmt.Errorf("manifest %s does not list any images", path)
+	}
+
+	for i, spec := range m.Images {
+		if spec.BucketName == "" || spec.ObjectName == "" || spec.ImageName == "" || spec.Region == "" {
+			return nil, fmt.Errorf("manifest %s: image %d is missing one of bucket, objectName, imageName, region", path, i)
+		}
+		if spec.InstanceID == "" && spec.InstanceName == "" {
+			return nil, fmt.Errorf("manifest %s: image %d is missing instanceID or instanceName", path, i)
+		}
+	}
+
+	return &m, nil
+}
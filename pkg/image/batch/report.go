@@ -0,0 +1,98 @@
+package batch
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+)
+
+// jsonResult is the JSON representation of a single Result.
+type jsonResult struct {
+	ImageName string  `json:"imageName"`
+	Bucket    string  `json:"bucket"`
+	Object    string  `json:"objectName"`
+	ImageID   string  `json:"imageID,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	Attempts  int     `json:"attempts"`
+	Seconds   float64 `json:"seconds"`
+}
+
+// Report is an aggregate summary of a batch run.
+type Report struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []jsonResult `json:"results"`
+}
+
+// NewReport summarizes results into a Report.
+func NewReport(results []Result) Report {
+	report := Report{Total: len(results)}
+	for _, r := range results {
+		jr := jsonResult{
+			ImageName: r.Spec.ImageName,
+			Bucket:    r.Spec.BucketName,
+			Object:    r.Spec.ObjectName,
+			ImageID:   r.ImageID,
+			Attempts:  r.Attempts,
+			Seconds:   r.Duration.Seconds(),
+		}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+		report.Results = append(report.Results, jr)
+	}
+	return report
+}
+
+// WriteJSON writes the report as JSON to w.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// junitTestSuite mirrors the subset of the JUnit XML schema CI systems parse.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    float64       `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the report as JUnit XML to w.
+func (r Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:     "pvsadm-image-batch-import",
+		Tests:    r.Total,
+		Failures: r.Failed,
+	}
+	for _, res := range r.Results {
+		tc := junitTestCase{Name: res.ImageName, Time: res.Seconds}
+		if res.Error != "" {
+			tc.Failure = &junitFailure{Message: res.Error}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(suite)
+}
@@ -0,0 +1,119 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Importer performs a single import for spec and returns the resulting image
+// ID. It is supplied by the caller so this package stays independent of how
+// the PowerVS/COS clients are constructed.
+type Importer func(ctx context.Context, spec Spec) (imageID string, err error)
+
+// RunOptions controls the worker pool driving a batch import.
+type RunOptions struct {
+	// MaxParallel is the maximum number of imports run concurrently.
+	MaxParallel int
+	// Retries is how many additional attempts are made for a spec after a
+	// transient failure, with exponential backoff between attempts.
+	Retries int
+	// RetryBackoff is the delay before the first retry.
+	RetryBackoff time.Duration
+	// IsTransient classifies whether err is worth retrying. Defaults to
+	// always retrying when nil.
+	IsTransient func(err error) bool
+}
+
+// Result is the outcome of importing a single Spec.
+type Result struct {
+	Spec     Spec
+	ImageID  string
+	Err      error
+	Attempts int
+	Duration time.Duration
+}
+
+// Run imports every spec in manifest.Images through importer, using up to
+// opts.MaxParallel workers, and returns one Result per spec in the order the
+// imports completed. Specs not yet handed to a worker when ctx is done get no
+// Result.
+func Run(ctx context.Context, manifest *Manifest, importer Importer, opts RunOptions) []Result {
+	if opts.MaxParallel <= 0 {
+		opts.MaxParallel = 1
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 5 * time.Second
+	}
+	if opts.IsTransient == nil {
+		opts.IsTransient = func(err error) bool { return true }
+	}
+
+	specs := make(chan Spec)
+	results := make(chan Result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.MaxParallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for spec := range specs {
+				results <- runOne(ctx, spec, importer, opts)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(specs)
+		for _, spec := range manifest.Images {
+			select {
+			case specs <- spec:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	all := make([]Result, 0, len(manifest.Images))
+	for r := range results {
+		all = append(all, r)
+	}
+	return all
+}
+
+func runOne(ctx context.Context, spec Spec, importer Importer, opts RunOptions) Result {
+	start := time.Now()
+	backoff := opts.RetryBackoff
+
+	var lastErr error
+	var attempt int
+	for attempt = 1; attempt <= opts.Retries+1; attempt++ {
+		imageID, err := importer(ctx, spec)
+		if err == nil {
+			return Result{Spec: spec, ImageID: imageID, Attempts: attempt, Duration: time.Since(start)}
+		}
+
+		lastErr = err
+		if attempt > opts.Retries || !opts.IsTransient(err) {
+			break
+		}
+
+		klog.Warningf("import of %s (attempt %d/%d) failed, retrying in %s: %v", spec.ImageName, attempt, opts.Retries+1, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+			return Result{Spec: spec, Err: lastErr, Attempts: attempt, Duration: time.Since(start)}
+		}
+		backoff *= 2
+	}
+
+	return Result{Spec: spec, Err: lastErr, Attempts: attempt, Duration: time.Since(start)}
+}
@@ -0,0 +1,22 @@
+package batch
+
+import "regexp"
+
+// transientStatusCode matches a 5xx HTTP status code PowerVS/COS return for
+// failures that are expected to clear up on their own (overloaded backend,
+// rate limiting, momentary outage), as opposed to a permanently-bad request.
+// The \b word boundaries keep this from firing on an unrelated digit run
+// that merely contains "500" etc., e.g. an image ID or a byte count.
+var transientStatusCode = regexp.MustCompile(`\b(500|502|503|504)\b`)
+
+// IsTransient reports whether err looks like a transient 5xx failure from the
+// PowerVS/COS APIs, worth retrying, rather than a permanent failure such as a
+// bad image name or a failed pre-flight validation. doImport wraps the
+// underlying SDK errors with %v rather than %w, so the original status code
+// is only recoverable from the error text.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	return transientStatusCode.MatchString(err.Error())
+}
@@ -0,0 +1,33 @@
+package purge
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Duration wraps time.Duration so a policy file can use a Go duration
+// string like "720h" instead of a raw nanosecond count.
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
@@ -0,0 +1,100 @@
+package purge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+)
+
+// Decision is the outcome of evaluating one candidate image against the
+// policy.
+type Decision struct {
+	ImageID string `json:"imageID"`
+	Name    string `json:"name"`
+	Rule    int    `json:"rule"`
+	InUse   bool   `json:"inUse,omitempty"`
+	Kept    bool   `json:"kept"`
+	Reason  string `json:"reason"`
+}
+
+// Getter fetches the images matching a single rule. It is supplied by the
+// caller so this package stays independent of how the PowerVS client is
+// constructed; in practice it wraps image.Client.GetAllPurgeable.
+type Getter func(minAge, maxAge time.Duration, nameRegex string) ([]*models.ImageReference, error)
+
+// Plan evaluates every rule in policy through get, applies each rule's
+// keep-last-N-per-prefix exemption and the inUse exclusion set, and returns
+// one Decision per candidate image, deduplicated by image ID across rules.
+func Plan(policy *Policy, get Getter, inUse map[string]bool) ([]Decision, error) {
+	seen := map[string]bool{}
+	var decisions []Decision
+
+	for i, rule := range policy.Rules {
+		candidates, err := get(rule.MinAge.Duration, rule.MaxAge.Duration, rule.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		kept := keepLastNPerPrefix(candidates, rule.KeepLastN)
+		for _, img := range candidates {
+			if seen[*img.ImageID] {
+				continue
+			}
+			seen[*img.ImageID] = true
+
+			d := Decision{ImageID: *img.ImageID, Name: *img.Name, Rule: i}
+			switch {
+			case inUse[*img.ImageID]:
+				d.Kept = true
+				d.InUse = true
+				d.Reason = "image is attached to a PVM instance"
+			case kept[*img.ImageID]:
+				d.Kept = true
+				d.Reason = fmt.Sprintf("one of the newest %d images sharing its name prefix", rule.KeepLastN)
+			default:
+				d.Reason = fmt.Sprintf("matched rule %d", i)
+			}
+			decisions = append(decisions, d)
+		}
+	}
+
+	return decisions, nil
+}
+
+// keepLastNPerPrefix groups images by name prefix (the portion of the name
+// before its last "-") and returns the set of image IDs among the newest n
+// per prefix, which the caller should exempt from deletion.
+func keepLastNPerPrefix(images []*models.ImageReference, n int) map[string]bool {
+	kept := map[string]bool{}
+	if n <= 0 {
+		return kept
+	}
+
+	byPrefix := map[string][]*models.ImageReference{}
+	for _, img := range images {
+		p := prefix(*img.Name)
+		byPrefix[p] = append(byPrefix[p], img)
+	}
+
+	for _, group := range byPrefix {
+		sort.Slice(group, func(i, j int) bool {
+			return time.Time(*group[i].CreationDate).After(time.Time(*group[j].CreationDate))
+		})
+		for i := 0; i < len(group) && i < n; i++ {
+			kept[*group[i].ImageID] = true
+		}
+	}
+	return kept
+}
+
+// prefix returns the portion of name before its last "-", or the whole name
+// if it has none, e.g. "rhel-83-10032020" -> "rhel-83".
+func prefix(name string) string {
+	if idx := strings.LastIndex(name, "-"); idx != -1 {
+		return name[:idx]
+	}
+	return name
+}
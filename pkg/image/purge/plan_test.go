@@ -0,0 +1,111 @@
+package purge
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	"github.com/go-openapi/strfmt"
+)
+
+func newImage(id, name string, age time.Duration) *models.ImageReference {
+	created := strfmt.DateTime(time.Now().Add(-age))
+	return &models.ImageReference{
+		ImageID:      &id,
+		Name:         &name,
+		CreationDate: &created,
+	}
+}
+
+func TestKeepLastNPerPrefix(t *testing.T) {
+	images := []*models.ImageReference{
+		newImage("rhel-1", "rhel-83-old", 72*time.Hour),
+		newImage("rhel-2", "rhel-83-mid", 48*time.Hour),
+		newImage("rhel-3", "rhel-83-new", 1*time.Hour),
+		newImage("sles-1", "sles-15-only", 1*time.Hour),
+	}
+
+	kept := keepLastNPerPrefix(images, 2)
+
+	for _, id := range []string{"rhel-3", "rhel-2"} {
+		if !kept[id] {
+			t.Errorf("expected %s to be kept as one of the newest 2 rhel-83 images", id)
+		}
+	}
+	if kept["rhel-1"] {
+		t.Errorf("expected rhel-1 to not be kept, it is the oldest of 3 rhel-83 images with keepLastN=2")
+	}
+	if !kept["sles-1"] {
+		t.Errorf("expected sles-1 to be kept, it is the only image in its prefix group")
+	}
+}
+
+func TestKeepLastNPerPrefixZeroOrNegative(t *testing.T) {
+	images := []*models.ImageReference{
+		newImage("rhel-1", "rhel-83-old", 72*time.Hour),
+	}
+	if kept := keepLastNPerPrefix(images, 0); len(kept) != 0 {
+		t.Errorf("expected no images to be kept when keepLastN is 0, got %v", kept)
+	}
+	if kept := keepLastNPerPrefix(images, -1); len(kept) != 0 {
+		t.Errorf("expected no images to be kept when keepLastN is negative, got %v", kept)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	cases := map[string]string{
+		"rhel-83-10032020": "rhel-83",
+		"nodash":           "nodash",
+		"a-b-c":            "a-b",
+	}
+	for name, want := range cases {
+		if got := prefix(name); got != want {
+			t.Errorf("prefix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPlanInUseExclusion(t *testing.T) {
+	images := []*models.ImageReference{
+		newImage("img-1", "rhel-83-a", 72*time.Hour),
+		newImage("img-2", "rhel-83-b", 48*time.Hour),
+	}
+
+	policy := &Policy{Rules: []Rule{{MaxAge: Duration{24 * time.Hour}}}}
+	get := func(minAge, maxAge time.Duration, nameRegex string) ([]*models.ImageReference, error) {
+		return images, nil
+	}
+
+	decisions, err := Plan(policy, get, map[string]bool{"img-1": true})
+	if err != nil {
+		t.Fatalf("Plan returned an unexpected error: %v", err)
+	}
+	if len(decisions) != len(images) {
+		t.Fatalf("expected %d decisions, got %d", len(images), len(decisions))
+	}
+
+	byID := map[string]Decision{}
+	for _, d := range decisions {
+		byID[d.ImageID] = d
+	}
+
+	if d := byID["img-1"]; !d.Kept || !d.InUse {
+		t.Errorf("expected img-1 to be kept because it is in use, got %+v", d)
+	}
+	if d := byID["img-2"]; d.Kept {
+		t.Errorf("expected img-2 to not be kept, got %+v", d)
+	}
+}
+
+func TestPlanPropagatesGetterError(t *testing.T) {
+	policy := &Policy{Rules: []Rule{{}}}
+	wantErr := fmt.Errorf("boom")
+	get := func(minAge, maxAge time.Duration, nameRegex string) ([]*models.ImageReference, error) {
+		return nil, wantErr
+	}
+
+	if _, err := Plan(policy, get, nil); err == nil {
+		t.Fatal("expected Plan to propagate the getter error")
+	}
+}
@@ -0,0 +1,53 @@
+// Package purge turns the image client's regex/creation-date purgeable
+// filter into a policy-driven cleanup subsystem: a YAML file lists one or
+// more rules, each naming a pattern, an age window, and how many of the
+// newest matches per name prefix to always keep, so a single `pvsadm image
+// purge` invocation can be the whole body of a scheduled cleanup cronjob.
+package purge
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Rule describes one purge criterion. An image is a candidate for the rule
+// if its name matches NameRegex (when set) and its age falls inside
+// [MinAge, MaxAge) (when set). KeepLastN then exempts the newest KeepLastN
+// candidates sharing a name prefix from deletion.
+type Rule struct {
+	NameRegex string   `json:"nameRegex,omitempty"`
+	MinAge    Duration `json:"minAge,omitempty"`
+	MaxAge    Duration `json:"maxAge,omitempty"`
+	KeepLastN int      `json:"keepLastNPerPrefix,omitempty"`
+}
+
+// Policy is the top-level document read from --policy.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadPolicy reads and validates the policy file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %v", path, err)
+	}
+
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %v", path, err)
+	}
+
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("policy %s does not list any rules", path)
+	}
+	for i, r := range p.Rules {
+		if r.NameRegex == "" && r.MinAge.Duration == 0 && r.MaxAge.Duration == 0 {
+			return nil, fmt.Errorf("policy %s: rule %d has none of nameRegex, minAge, maxAge set", path, i)
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,37 @@
+package purge
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Report summarizes a purge run, so --dry-run output and the real run's
+// audit trail share the same shape.
+type Report struct {
+	DryRun  bool       `json:"dryRun"`
+	Total   int        `json:"total"`
+	Deleted int        `json:"deleted"`
+	Kept    int        `json:"kept"`
+	Images  []Decision `json:"images"`
+}
+
+// NewReport summarizes decisions into a Report. Deleted counts every
+// decision that was (or, under --dry-run, would have been) deleted.
+func NewReport(decisions []Decision, dryRun bool) Report {
+	r := Report{DryRun: dryRun, Total: len(decisions), Images: decisions}
+	for _, d := range decisions {
+		if d.Kept {
+			r.Kept++
+		} else {
+			r.Deleted++
+		}
+	}
+	return r
+}
+
+// WriteJSON writes the report as JSON to w.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
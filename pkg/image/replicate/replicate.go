@@ -0,0 +1,95 @@
+// Package replicate fans an existing PowerVS image out to one or more
+// target workspaces concurrently, so platform teams can seed every
+// workspace from a single source image with one pvsadm invocation instead
+// of scripting N imports.
+package replicate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Target is a single PowerVS workspace to import the replicated image into.
+type Target struct {
+	InstanceID   string
+	InstanceName string
+	Region       string
+	BucketName   string
+}
+
+// Importer stages and imports the source image for target and returns the
+// resulting image ID. It is supplied by the caller so this package stays
+// independent of how the PowerVS/COS clients are constructed.
+type Importer func(ctx context.Context, target Target) (imageID string, err error)
+
+// Result is the outcome of replicating to a single Target.
+type Result struct {
+	Target   Target
+	ImageID  string
+	Err      error
+	Duration time.Duration
+}
+
+// RunOptions controls the worker pool driving a replicate fan-out.
+type RunOptions struct {
+	// MaxParallel is the maximum number of targets imported concurrently.
+	MaxParallel int
+}
+
+// Run imports into every target through importer, using up to
+// opts.MaxParallel workers, and returns one Result per target in the order
+// the imports completed. Targets not yet handed to a worker when ctx is done
+// get no Result.
+func Run(ctx context.Context, targets []Target, importer Importer, opts RunOptions) []Result {
+	if opts.MaxParallel <= 0 {
+		opts.MaxParallel = 1
+	}
+
+	in := make(chan Target)
+	out := make(chan Result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.MaxParallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range in {
+				out <- runOne(ctx, target, importer)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(in)
+		for _, target := range targets {
+			select {
+			case in <- target:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	results := make([]Result, 0, len(targets))
+	for r := range out {
+		results = append(results, r)
+	}
+	return results
+}
+
+func runOne(ctx context.Context, target Target, importer Importer) Result {
+	start := time.Now()
+	imageID, err := importer(ctx, target)
+	if err != nil {
+		klog.Warningf("replicate to %s (%s) failed: %v", target.InstanceName, target.Region, err)
+	}
+	return Result{Target: target, ImageID: imageID, Err: err, Duration: time.Since(start)}
+}
@@ -0,0 +1,208 @@
+// Package validate runs pre-flight checks against the object pvsadm is about
+// to hand to the PowerVS import API, so obviously bad inputs (missing
+// objects, truncated OVAs, checksum mismatches) fail fast with an actionable
+// error instead of a multi-minute round trip through a PowerVS import job.
+package validate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// MaxImageSizeBytes is the largest object PowerVS will accept for import.
+const MaxImageSizeBytes int64 = 1 << 40 // 1 TiB
+
+// sniffBytes is how much of the object head is downloaded to sanity check
+// that it looks like a gzip'd OVA before committing to a full checksum pass.
+const sniffBytes = 8 << 20 // 8 MiB
+
+// rangeChunkBytes is the read size used while streaming the object for
+// checksum validation.
+const rangeChunkBytes = 32 << 20 // 32 MiB
+
+// Options configures which checks Validate performs. Leaving SHA256 and
+// ChecksumFile empty skips the checksum verification step.
+type Options struct {
+	// SHA256 is an expected hex-encoded digest of the object.
+	SHA256 string
+	// ChecksumFile is a path to a sha256sum(1)-style file ("<digest>  <name>")
+	// containing the expected digest for the object.
+	ChecksumFile string
+}
+
+// Validate runs the pre-flight checks against bucket/object and returns the
+// first failure encountered.
+func Validate(s3client *s3.S3, bucket, object string, opts Options) error {
+	size, err := checkSize(s3client, bucket, object)
+	if err != nil {
+		return err
+	}
+
+	if err := checkOVAStructure(s3client, bucket, object); err != nil {
+		return err
+	}
+
+	expected := opts.SHA256
+	if expected == "" && opts.ChecksumFile != "" {
+		expected, err = readExpectedChecksum(opts.ChecksumFile, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expected != "" {
+		if err := checkChecksum(s3client, bucket, object, size, expected); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSize HEADs the object and rejects it if it is empty or larger than
+// PowerVS can import.
+func checkSize(s3client *s3.S3, bucket, object string) (int64, error) {
+	head, err := s3client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to HEAD %s/%s: %v", bucket, object, err)
+	}
+
+	size := aws.Int64Value(head.ContentLength)
+	if size == 0 {
+		return 0, fmt.Errorf("%s/%s is a zero-byte object", bucket, object)
+	}
+	if size > MaxImageSizeBytes {
+		return 0, fmt.Errorf("%s/%s is %d bytes, which exceeds the PowerVS import limit of %d bytes", bucket, object, size, MaxImageSizeBytes)
+	}
+
+	return size, nil
+}
+
+// checkOVAStructure downloads the first sniffBytes of the object and verifies
+// it is a gzip stream containing a tar archive with the .ovf/.vmdk members
+// PowerVS expects.
+func checkOVAStructure(s3client *s3.S3, bucket, object string) error {
+	out, err := s3client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", sniffBytes-1)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the head of %s/%s: %v", bucket, object, err)
+	}
+	defer out.Body.Close()
+
+	head, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the head of %s/%s: %v", bucket, object, err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return fmt.Errorf("%s/%s does not look like a gzip'd OVA: %v", bucket, object, err)
+	}
+	defer gz.Close()
+
+	var hasOVF, hasVMDK bool
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			// We only have a partial read of the object, so running out of
+			// tar data here is expected once we've seen enough members.
+			break
+		}
+		switch {
+		case strings.HasSuffix(hdr.Name, ".ovf"):
+			hasOVF = true
+		case strings.HasSuffix(hdr.Name, ".vmdk"):
+			hasVMDK = true
+		}
+		if hasOVF && hasVMDK {
+			break
+		}
+	}
+
+	if !hasOVF || !hasVMDK {
+		return fmt.Errorf("%s/%s does not contain the expected .ovf and .vmdk members", bucket, object)
+	}
+
+	return nil
+}
+
+// checkChecksum streams the full object in ranged chunks and compares its
+// sha256 digest against expected.
+func checkChecksum(s3client *s3.S3, bucket, object string, size int64, expected string) error {
+	h := sha256.New()
+	for start := int64(0); start < size; start += rangeChunkBytes {
+		end := start + rangeChunkBytes - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		out, err := s3client.GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(object),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read bytes %d-%d of %s/%s: %v", start, end, bucket, object, err)
+		}
+
+		_, err = io.Copy(h, out.Body)
+		out.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read bytes %d-%d of %s/%s: %v", start, end, bucket, object, err)
+		}
+	}
+
+	got := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(got, expected) {
+		return fmt.Errorf("checksum mismatch for %s/%s: expected %s, got %s", bucket, object, expected, got)
+	}
+
+	return nil
+}
+
+// readExpectedChecksum reads a sha256sum(1)-style file and returns the digest
+// listed for object, matching either the full object key or its base name.
+func readExpectedChecksum(path, object string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksum file %s: %v", path, err)
+	}
+
+	base := object
+	if idx := strings.LastIndex(object, "/"); idx != -1 {
+		base = object[idx+1:]
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		digest, name := fields[0], strings.TrimPrefix(fields[1], "*")
+		if name == object || name == base {
+			return digest, nil
+		}
+	}
+
+	return "", fmt.Errorf("no checksum entry for %s found in %s", object, path)
+}
@@ -0,0 +1,200 @@
+package validate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// buildOVAFixture returns a gzip'd tar archive with a .ovf and .vmdk member,
+// like the object checkOVAStructure expects to find at the head of a real
+// OVA upload.
+func buildOVAFixture(t *testing.T) []byte {
+	t.Helper()
+
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for _, name := range []string{"disk.ovf", "disk.vmdk"} {
+		content := []byte("fixture content for " + name)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content))}); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("failed to write tar content: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	var gzBuf bytes.Buffer
+	gw := gzip.NewWriter(&gzBuf)
+	if _, err := gw.Write(tarBuf.Bytes()); err != nil {
+		t.Fatalf("failed to write gzip content: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	return gzBuf.Bytes()
+}
+
+// newFakeS3 starts an httptest server standing in for a bucket holding a
+// single object, and returns an *s3.S3 client pointed at it.
+func newFakeS3(t *testing.T, body []byte) *s3.S3 {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(server.URL),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		DisableSSL:       aws.Bool(true),
+		S3ForcePathStyle: aws.Bool(true),
+	}))
+	return s3.New(sess)
+}
+
+func TestCheckOVAStructure(t *testing.T) {
+	s3client := newFakeS3(t, buildOVAFixture(t))
+	if err := checkOVAStructure(s3client, "bucket", "object.ova.gz"); err != nil {
+		t.Errorf("expected a valid OVA fixture to pass, got: %v", err)
+	}
+}
+
+func TestCheckOVAStructureRejectsNonGzip(t *testing.T) {
+	s3client := newFakeS3(t, []byte("not a gzip stream"))
+	if err := checkOVAStructure(s3client, "bucket", "object.ova.gz"); err == nil {
+		t.Error("expected a non-gzip object to fail OVA structure validation")
+	}
+}
+
+func TestCheckOVAStructureRejectsMissingMembers(t *testing.T) {
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	if err := tw.WriteHeader(&tar.Header{Name: "readme.txt", Size: 5}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte("hello")); err != nil {
+		t.Fatalf("failed to write tar content: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	var gzBuf bytes.Buffer
+	gw := gzip.NewWriter(&gzBuf)
+	if _, err := gw.Write(tarBuf.Bytes()); err != nil {
+		t.Fatalf("failed to write gzip content: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+
+	s3client := newFakeS3(t, gzBuf.Bytes())
+	if err := checkOVAStructure(s3client, "bucket", "object.ova.gz"); err == nil {
+		t.Error("expected an archive missing .ovf/.vmdk members to fail OVA structure validation")
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	body := buildOVAFixture(t)
+	s3client := newFakeS3(t, body)
+
+	size, err := checkSize(s3client, "bucket", "object.ova.gz")
+	if err != nil {
+		t.Fatalf("checkSize returned an unexpected error: %v", err)
+	}
+	if size != int64(len(body)) {
+		t.Errorf("expected size %d, got %d", len(body), size)
+	}
+}
+
+func TestCheckSizeRejectsZeroByteObject(t *testing.T) {
+	s3client := newFakeS3(t, nil)
+	if _, err := checkSize(s3client, "bucket", "object.ova.gz"); err == nil {
+		t.Error("expected a zero-byte object to fail the size check")
+	}
+}
+
+func TestCheckChecksum(t *testing.T) {
+	body := buildOVAFixture(t)
+	s3client := newFakeS3(t, body)
+
+	sum := sha256.Sum256(body)
+	expected := hex.EncodeToString(sum[:])
+
+	if err := checkChecksum(s3client, "bucket", "object.ova.gz", int64(len(body)), expected); err != nil {
+		t.Errorf("expected the checksum to match, got: %v", err)
+	}
+	if err := checkChecksum(s3client, "bucket", "object.ova.gz", int64(len(body)), "0000000000000000000000000000000000000000000000000000000000000"); err == nil {
+		t.Error("expected a mismatched checksum to fail")
+	}
+}
+
+func TestReadExpectedChecksum(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "SHA256SUMS")
+	contents := "deadbeef  rhel-83-10032020.ova.gz\ncafef00d  *other-image.ova.gz\n"
+	if err := ioutil.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write fixture checksum file: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		object  string
+		want    string
+		wantErr bool
+	}{
+		{name: "exact match", object: "rhel-83-10032020.ova.gz", want: "deadbeef"},
+		{name: "base name match against a prefixed object key", object: "uploads/rhel-83-10032020.ova.gz", want: "deadbeef"},
+		{name: "entry with a leading * marker", object: "other-image.ova.gz", want: "cafef00d"},
+		{name: "no matching entry", object: "missing.ova.gz", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := readExpectedChecksum(path, c.object)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for object %q, got digest %q", c.object, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readExpectedChecksum returned an unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("readExpectedChecksum(%q) = %q, want %q", c.object, got, c.want)
+			}
+		})
+	}
+}
+
+func TestReadExpectedChecksumMissingFile(t *testing.T) {
+	if _, err := readExpectedChecksum(filepath.Join(t.TempDir(), "missing"), "object"); err == nil {
+		t.Error("expected a missing checksum file to return an error")
+	}
+}
@@ -0,0 +1,166 @@
+// Package jobs persists a local log of PowerVS image import jobs so that
+// pvsadm can resume tracking them across separate invocations, e.g. after
+// `pvsadm image import --wait=false` returns immediately.
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Record is a single tracked import job.
+type Record struct {
+	ImageID    string `json:"imageID"`
+	ImageName  string `json:"imageName"`
+	InstanceID string `json:"instanceID"`
+	State      string `json:"state"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+// Store is a flat JSON-backed log of Records, keyed by ImageID.
+type Store struct {
+	path string
+	mu   *sync.Mutex
+}
+
+// storeLocks holds one mutex per job log path, shared by every *Store opened
+// on that path, so that concurrent Upsert/Remove calls from parallel import
+// workers (each of which opens its own *Store) serialize their read-modify-
+// write of the underlying file instead of racing and dropping updates.
+var (
+	storeLocksMu sync.Mutex
+	storeLocks   = map[string]*sync.Mutex{}
+)
+
+func lockFor(path string) *sync.Mutex {
+	storeLocksMu.Lock()
+	defer storeLocksMu.Unlock()
+	mu, ok := storeLocks[path]
+	if !ok {
+		mu = &sync.Mutex{}
+		storeLocks[path] = mu
+	}
+	return mu
+}
+
+// DefaultPath returns ~/.pvsadm/jobs.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the user home directory: %v", err)
+	}
+	return filepath.Join(home, ".pvsadm", "jobs.json"), nil
+}
+
+// NewStore opens the job log at path, creating the parent directory if
+// necessary. The file itself is created lazily on the first Upsert or Remove.
+func NewStore(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the job log directory: %v", err)
+	}
+	return &Store{path: path, mu: lockFor(path)}, nil
+}
+
+// List returns all tracked jobs.
+func (s *Store) List() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// read loads the records from disk. Callers must hold s.mu.
+func (s *Store) read() ([]Record, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the job log %s: %v", s.path, err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse the job log %s: %v", s.path, err)
+	}
+	return records, nil
+}
+
+// Get returns the record for imageID, or false if it is not tracked.
+func (s *Store) Get(imageID string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read()
+	if err != nil {
+		return Record{}, false, err
+	}
+	for _, r := range records {
+		if r.ImageID == imageID {
+			return r, true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// Upsert adds r to the log, replacing any existing record with the same
+// ImageID. It is safe to call concurrently, including from separate *Store
+// values opened on the same path.
+func (s *Store) Upsert(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range records {
+		if records[i].ImageID == r.ImageID {
+			records[i] = r
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		records = append(records, r)
+	}
+
+	return s.write(records)
+}
+
+// Remove deletes the record for imageID from the log, if present. It is safe
+// to call concurrently, including from separate *Store values opened on the
+// same path.
+func (s *Store) Remove(imageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	filtered := records[:0]
+	for _, r := range records {
+		if r.ImageID != imageID {
+			filtered = append(filtered, r)
+		}
+	}
+	return s.write(filtered)
+}
+
+func (s *Store) write(records []Record) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the job log: %v", err)
+	}
+	if err := ioutil.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write the job log %s: %v", s.path, err)
+	}
+	return nil
+}
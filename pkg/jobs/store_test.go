@@ -0,0 +1,139 @@
+package jobs
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(filepath.Join(t.TempDir(), "jobs.json"))
+	if err != nil {
+		t.Fatalf("NewStore returned an unexpected error: %v", err)
+	}
+	return s
+}
+
+func TestStoreUpsertAddsAndReplaces(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.Upsert(Record{ImageID: "img-1", State: "queued"}); err != nil {
+		t.Fatalf("Upsert returned an unexpected error: %v", err)
+	}
+	if err := s.Upsert(Record{ImageID: "img-2", State: "queued"}); err != nil {
+		t.Fatalf("Upsert returned an unexpected error: %v", err)
+	}
+	if err := s.Upsert(Record{ImageID: "img-1", State: "active"}); err != nil {
+		t.Fatalf("Upsert returned an unexpected error: %v", err)
+	}
+
+	records, err := s.List()
+	if err != nil {
+		t.Fatalf("List returned an unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d: %+v", len(records), records)
+	}
+
+	r, ok, err := s.Get("img-1")
+	if err != nil {
+		t.Fatalf("Get returned an unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected img-1 to be tracked")
+	}
+	if r.State != "active" {
+		t.Errorf("expected the img-1 record to be replaced with state active, got %q", r.State)
+	}
+}
+
+func TestStoreRemove(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.Upsert(Record{ImageID: "img-1"}); err != nil {
+		t.Fatalf("Upsert returned an unexpected error: %v", err)
+	}
+	if err := s.Remove("img-1"); err != nil {
+		t.Fatalf("Remove returned an unexpected error: %v", err)
+	}
+
+	if _, ok, err := s.Get("img-1"); err != nil {
+		t.Fatalf("Get returned an unexpected error: %v", err)
+	} else if ok {
+		t.Error("expected img-1 to no longer be tracked after Remove")
+	}
+}
+
+func TestStoreConcurrentUpsertAcrossSeparateInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each worker opens its own *Store over the same path, mirroring
+			// how the manifest import worker pool uses jobs.Store.
+			s, err := NewStore(path)
+			if err != nil {
+				t.Errorf("NewStore returned an unexpected error: %v", err)
+				return
+			}
+			if err := s.Upsert(Record{ImageID: filepath.Join("img", filepath.Base(path)), State: "queued"}); err != nil {
+				t.Errorf("Upsert returned an unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore returned an unexpected error: %v", err)
+	}
+	records, err := s.List()
+	if err != nil {
+		t.Fatalf("List returned an unexpected error: %v", err)
+	}
+	// All n workers race to upsert the same ImageID, so a correct store ends
+	// up with exactly one record rather than a mix of lost or duplicated
+	// writes.
+	if len(records) != 1 {
+		t.Fatalf("expected concurrent upserts of the same ImageID to collapse to 1 record, got %d: %+v", len(records), records)
+	}
+}
+
+func TestStoreConcurrentUpsertDistinctKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s, err := NewStore(path)
+			if err != nil {
+				t.Errorf("NewStore returned an unexpected error: %v", err)
+				return
+			}
+			if err := s.Upsert(Record{ImageID: filepath.Base(t.TempDir())}); err != nil {
+				t.Errorf("Upsert returned an unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore returned an unexpected error: %v", err)
+	}
+	records, err := s.List()
+	if err != nil {
+		t.Fatalf("List returned an unexpected error: %v", err)
+	}
+	if len(records) != n {
+		t.Fatalf("expected %d records from %d concurrent upserts of distinct keys, got %d", n, n, len(records))
+	}
+}